@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"encoding/xml"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -31,6 +33,10 @@ var AggregateResultsCmd = &cobra.Command{
 		githubWorkflowName, _ := cmd.Flags().GetString("github-workflow-name")
 		githubWorkflowRunURL, _ := cmd.Flags().GetString("github-workflow-run-url")
 		reportID, _ := cmd.Flags().GetString("report-id")
+		extractDir, _ := cmd.Flags().GetString("extract-dir")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		junitOutputPath, _ := cmd.Flags().GetString("junit-output")
+		githubAnnotations, _ := cmd.Flags().GetBool("github-annotations")
 
 		initialDirSize, err := getDirSize(resultsPath)
 		if err != nil {
@@ -62,6 +68,8 @@ var AggregateResultsCmd = &cobra.Command{
 			reports.WithRepoURL(repoURL),
 			reports.WithGitHubWorkflowName(githubWorkflowName),
 			reports.WithGitHubWorkflowRunURL(githubWorkflowRunURL),
+			reports.WithExtractDir(extractDir),
+			reports.WithConcurrency(concurrency),
 		)
 		if err != nil {
 			s.Stop()
@@ -71,85 +79,127 @@ var AggregateResultsCmd = &cobra.Command{
 		s.Stop()
 		log.Debug().Msg("Successfully loaded and aggregated test reports")
 
-		// Start spinner for mapping test results to paths
+		// Start spinner for writing the reports
 		s = spinner.New(spinner.CharSets[11], 100*time.Millisecond)
-		s.Suffix = " Filter failed tests..."
+		s.Suffix = " Writing test reports..."
 		s.Start()
 
-		failedTests := reports.FilterTests(aggregatedReport.Results, func(tr reports.TestResult) bool {
+		hasFailedTests := len(reports.FilterTests(aggregatedReport.Results, func(tr reports.TestResult) bool {
 			return !tr.Skipped && tr.PassRatio < maxPassRatio
-		})
-		s.Stop()
+		})) > 0
 
-		// Check if there are any failed tests
-		if len(failedTests) > 0 {
-			log.Info().Int("count", len(failedTests)).Msg("Found failed tests")
-
-			// Create a new report for failed tests with logs
-			failedReportWithLogs := &reports.TestReport{
-				GoProject:          aggregatedReport.GoProject,
-				SummaryData:        aggregatedReport.SummaryData,
-				RaceDetection:      aggregatedReport.RaceDetection,
-				ExcludedTests:      aggregatedReport.ExcludedTests,
-				SelectedTests:      aggregatedReport.SelectedTests,
-				HeadSHA:            aggregatedReport.HeadSHA,
-				BaseSHA:            aggregatedReport.BaseSHA,
-				GitHubWorkflowName: aggregatedReport.GitHubWorkflowName,
-				Results:            failedTests,
-			}
+		allResultsPath := filepath.Join(outputDir, "all-test-results.json")
+		allEncoder, allFile, err := openStreamEncoder(fs, allResultsPath, *aggregatedReport)
+		if err != nil {
+			s.Stop()
+			log.Error().Stack().Err(err).Msg("Error opening aggregated test report")
+			os.Exit(ErrorExitCode)
+		}
+		defer allFile.Close()
 
-			// Save the failed tests report with logs
-			failedTestsReportWithLogsPath := filepath.Join(outputDir, "failed-test-results-with-logs.json")
-			if err := reports.SaveReport(fs, failedTestsReportWithLogsPath, *failedReportWithLogs); err != nil {
-				log.Error().Stack().Err(err).Msg("Error saving failed tests report with logs")
+		var failedWithLogsEncoder, failedNoLogsEncoder *reports.StreamEncoder
+		var failedWithLogsFile, failedNoLogsFile io.WriteCloser
+		failedWithLogsPath := filepath.Join(outputDir, "failed-test-results-with-logs.json")
+		failedNoLogsPath := filepath.Join(outputDir, "failed-test-results.json")
+		if hasFailedTests {
+			failedReportHeader := *aggregatedReport
+			failedReportHeader.Results = nil
+
+			failedWithLogsEncoder, failedWithLogsFile, err = openStreamEncoder(fs, failedWithLogsPath, failedReportHeader)
+			if err != nil {
+				s.Stop()
+				log.Error().Stack().Err(err).Msg("Error opening failed tests report with logs")
 				os.Exit(ErrorExitCode)
 			}
-			log.Debug().Str("path", failedTestsReportWithLogsPath).Msg("Failed tests report with logs saved")
+			defer failedWithLogsFile.Close()
 
-			// Remove logs from test results for the report without logs
-			for i := range failedReportWithLogs.Results {
-				failedReportWithLogs.Results[i].PassedOutputs = nil
-				failedReportWithLogs.Results[i].FailedOutputs = nil
-				failedReportWithLogs.Results[i].PackageOutputs = nil
+			failedNoLogsEncoder, failedNoLogsFile, err = openStreamEncoder(fs, failedNoLogsPath, failedReportHeader)
+			if err != nil {
+				s.Stop()
+				log.Error().Stack().Err(err).Msg("Error opening failed tests report without logs")
+				os.Exit(ErrorExitCode)
 			}
+			defer failedNoLogsFile.Close()
+		}
 
-			// Save the failed tests report without logs
-			failedTestsReportNoLogsPath := filepath.Join(outputDir, "failed-test-results.json")
-			if err := reports.SaveReport(fs, failedTestsReportNoLogsPath, *failedReportWithLogs); err != nil {
-				log.Error().Stack().Err(err).Msg("Error saving failed tests report without logs")
+		// Single pass over the aggregated results: write the no-logs variant
+		// to every applicable output, and the with-logs variant only to the
+		// failed-tests-with-logs report.
+		failedCount := 0
+		for _, tr := range aggregatedReport.Results {
+			if err := allEncoder.WriteResult(reports.StripLogs(tr)); err != nil {
+				s.Stop()
+				log.Error().Stack().Err(err).Msg("Error writing aggregated test report")
 				os.Exit(ErrorExitCode)
 			}
-			log.Debug().Str("path", failedTestsReportNoLogsPath).Msg("Failed tests report without logs saved")
-		} else {
-			log.Debug().Msg("No failed tests found. Skipping generation of failed tests reports")
-		}
 
-		// Remove logs from test results for the aggregated report
-		for i := range aggregatedReport.Results {
-			aggregatedReport.Results[i].PassedOutputs = nil
-			aggregatedReport.Results[i].FailedOutputs = nil
-			aggregatedReport.Results[i].PackageOutputs = nil
+			if tr.Skipped || tr.PassRatio >= maxPassRatio {
+				continue
+			}
+			failedCount++
+
+			if err := failedWithLogsEncoder.WriteResult(tr); err != nil {
+				s.Stop()
+				log.Error().Stack().Err(err).Msg("Error writing failed tests report with logs")
+				os.Exit(ErrorExitCode)
+			}
+			if err := failedNoLogsEncoder.WriteResult(reports.StripLogs(tr)); err != nil {
+				s.Stop()
+				log.Error().Stack().Err(err).Msg("Error writing failed tests report without logs")
+				os.Exit(ErrorExitCode)
+			}
 		}
 
-		// Save the aggregated report to the output directory
-		aggregatedReportPath := filepath.Join(outputDir, "all-test-results.json")
-		if err := reports.SaveReport(fs, aggregatedReportPath, *aggregatedReport); err != nil {
-			log.Error().Stack().Err(err).Msg("Error saving aggregated test report")
+		if err := allEncoder.Close(); err != nil {
+			s.Stop()
+			log.Error().Stack().Err(err).Msg("Error finalizing aggregated test report")
 			os.Exit(ErrorExitCode)
 		}
+		if hasFailedTests {
+			log.Info().Int("count", failedCount).Msg("Found failed tests")
+			if err := failedWithLogsEncoder.Close(); err != nil {
+				s.Stop()
+				log.Error().Stack().Err(err).Msg("Error finalizing failed tests report with logs")
+				os.Exit(ErrorExitCode)
+			}
+			if err := failedNoLogsEncoder.Close(); err != nil {
+				s.Stop()
+				log.Error().Stack().Err(err).Msg("Error finalizing failed tests report without logs")
+				os.Exit(ErrorExitCode)
+			}
+			log.Debug().Str("path", failedWithLogsPath).Msg("Failed tests report with logs saved")
+			log.Debug().Str("path", failedNoLogsPath).Msg("Failed tests report without logs saved")
+		} else {
+			log.Debug().Msg("No failed tests found. Skipping generation of failed tests reports")
+		}
+		s.Stop()
 
 		finalDirSize, err := getDirSize(resultsPath)
 		if err != nil {
 			log.Error().Err(err).Str("path", resultsPath).Msg("Error getting final directory size")
 			// intentionally don't exit here, as we can still proceed with the aggregation
 		}
+		if junitOutputPath != "" {
+			if err := writeJUnitReport(fs, junitOutputPath, *aggregatedReport); err != nil {
+				log.Error().Stack().Err(err).Msg("Error writing JUnit report")
+				os.Exit(ErrorExitCode)
+			}
+			log.Debug().Str("path", junitOutputPath).Msg("JUnit report saved")
+		}
+
+		if githubAnnotations {
+			for _, annotation := range reports.BuildGitHubAnnotations(*aggregatedReport, repoPath, maxPassRatio) {
+				fmt.Println(annotation.String())
+			}
+		}
+
 		diskSpaceUsed := byteCountSI(finalDirSize - initialDirSize)
-		log.Info().Str("disk space used", diskSpaceUsed).Str("report", aggregatedReportPath).Msg("Aggregation complete")
+		log.Info().Str("disk space used", diskSpaceUsed).Str("report", allResultsPath).Msg("Aggregation complete")
 	},
 }
 
 func init() {
-	AggregateResultsCmd.Flags().StringP("results-path", "p", "", "Path to the folder containing JSON test result files (required)")
+	AggregateResultsCmd.Flags().StringP("results-path", "p", "", "Path to the folder containing JSON test result files, or a .tar.gz/.tgz/.zip archive (or folder of archives) of them (required)")
 	AggregateResultsCmd.Flags().StringP("output-path", "o", "./report", "Path to output the aggregated results (directory)")
 	AggregateResultsCmd.Flags().Float64P("max-pass-ratio", "", 1.0, "The maximum pass ratio threshold for a test to be considered flaky")
 	AggregateResultsCmd.Flags().StringP("codeowners-path", "", "", "Path to the CODEOWNERS file")
@@ -161,12 +211,55 @@ func init() {
 	AggregateResultsCmd.Flags().String("github-workflow-name", "", "GitHub workflow name for the test report")
 	AggregateResultsCmd.Flags().String("github-workflow-run-url", "", "GitHub workflow run URL for the test report")
 	AggregateResultsCmd.Flags().String("report-id", "", "Optional identifier for the test report. Will be generated if not provided")
+	AggregateResultsCmd.Flags().String("extract-dir", "", "Directory to extract results-path archives into before aggregating (defaults to the OS temp directory)")
+	AggregateResultsCmd.Flags().Int("concurrency", 0, "Number of results files to parse in parallel (defaults to runtime.NumCPU())")
+	AggregateResultsCmd.Flags().String("junit-output", "", "If set, also write the aggregated report as JUnit XML to this path")
+	AggregateResultsCmd.Flags().Bool("github-annotations", false, "If set, emit GitHub Actions ::error/::warning annotations for failed and flaky tests")
 
 	if err := AggregateResultsCmd.MarkFlagRequired("results-path"); err != nil {
 		log.Fatal().Err(err).Msg("Error marking flag as required")
 	}
 }
 
+// openStreamEncoder creates path via fs and opens a reports.StreamEncoder
+// against it, pre-populated with header's metadata. The returned
+// io.WriteCloser must be closed by the caller after the encoder itself is
+// closed.
+func openStreamEncoder(fs reports.FileSystem, path string, header reports.TestReport) (*reports.StreamEncoder, io.WriteCloser, error) {
+	if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, nil, fmt.Errorf("creating output directory for %q: %w", path, err)
+	}
+	f, err := fs.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating %q: %w", path, err)
+	}
+	enc, err := reports.NewStreamEncoder(f, header)
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("opening report stream for %q: %w", path, err)
+	}
+	return enc, f, nil
+}
+
+// writeJUnitReport converts report into a JUnit testsuites document and
+// writes it to path via fs.
+func writeJUnitReport(fs reports.FileSystem, path string, report reports.TestReport) error {
+	suites := reports.BuildJUnitReport(report)
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling JUnit report: %w", err)
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating output directory for %q: %w", path, err)
+	}
+	full := append([]byte(xml.Header), data...)
+	if err := fs.WriteFile(path, full, 0644); err != nil {
+		return fmt.Errorf("writing %q: %w", path, err)
+	}
+	return nil
+}
+
 // getDirSize returns the size of a directory in bytes
 // helpful for tracking how much data is being produced on disk
 func getDirSize(path string) (int64, error) {
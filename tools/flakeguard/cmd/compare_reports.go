@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	"github.com/smartcontractkit/chainlink-testing-framework/tools/flakeguard/reports"
+	"github.com/spf13/cobra"
+)
+
+// CompareReportsCmd diffs two aggregate-results outputs and reports which
+// tests got more or less flaky between them.
+var CompareReportsCmd = &cobra.Command{
+	Use:   "compare-reports",
+	Short: "Compare two aggregated test reports for flakiness regressions",
+	Run: func(cmd *cobra.Command, args []string) {
+		baselinePath, _ := cmd.Flags().GetString("baseline")
+		currentPath, _ := cmd.Flags().GetString("current")
+		outputDir, _ := cmd.Flags().GetString("output-path")
+		maxPassRatio, _ := cmd.Flags().GetFloat64("max-pass-ratio")
+		significance, _ := cmd.Flags().GetFloat64("significance")
+		minRuns, _ := cmd.Flags().GetInt("min-runs")
+
+		baseline, err := readTestReport(baselinePath)
+		if err != nil {
+			log.Error().Err(err).Str("path", baselinePath).Msg("Error reading baseline report")
+			os.Exit(ErrorExitCode)
+		}
+
+		current, err := readTestReport(currentPath)
+		if err != nil {
+			log.Error().Err(err).Str("path", currentPath).Msg("Error reading current report")
+			os.Exit(ErrorExitCode)
+		}
+
+		comparison := reports.CompareReports(*baseline, *current, reports.CompareOptions{
+			MaxPassRatio: maxPassRatio,
+			Significance: significance,
+			MinRuns:      minRuns,
+		})
+
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			log.Error().Err(err).Str("path", outputDir).Msg("Error creating output directory")
+			os.Exit(ErrorExitCode)
+		}
+
+		jsonPath := filepath.Join(outputDir, "comparison.json")
+		data, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			log.Error().Err(err).Msg("Error marshalling comparison report")
+			os.Exit(ErrorExitCode)
+		}
+		if err := os.WriteFile(jsonPath, data, 0644); err != nil {
+			log.Error().Err(err).Str("path", jsonPath).Msg("Error writing comparison JSON")
+			os.Exit(ErrorExitCode)
+		}
+
+		markdownPath := filepath.Join(outputDir, "comparison.md")
+		if err := os.WriteFile(markdownPath, []byte(comparison.RenderMarkdown()), 0644); err != nil {
+			log.Error().Err(err).Str("path", markdownPath).Msg("Error writing comparison markdown")
+			os.Exit(ErrorExitCode)
+		}
+
+		log.Info().Str("json", jsonPath).Str("markdown", markdownPath).Msg("Comparison complete")
+	},
+}
+
+func readTestReport(path string) (*reports.TestReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report reports.TestReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func init() {
+	CompareReportsCmd.Flags().String("baseline", "", "Path to the baseline all-test-results.json (required)")
+	CompareReportsCmd.Flags().String("current", "", "Path to the current all-test-results.json (required)")
+	CompareReportsCmd.Flags().StringP("output-path", "o", "./report", "Path to output the comparison report (directory)")
+	CompareReportsCmd.Flags().Float64("max-pass-ratio", 1.0, "The maximum pass ratio threshold for a test to be considered flaky")
+	CompareReportsCmd.Flags().Float64("significance", 0.05, "p-value threshold below which a pass-ratio change is trusted as Regressed/Fixed rather than NoisyChange")
+	CompareReportsCmd.Flags().Int("min-runs", 10, "Minimum runs required in both reports before applying the significance test")
+
+	if err := CompareReportsCmd.MarkFlagRequired("baseline"); err != nil {
+		log.Fatal().Err(err).Msg("Error marking flag as required")
+	}
+	if err := CompareReportsCmd.MarkFlagRequired("current"); err != nil {
+		log.Fatal().Err(err).Msg("Error marking flag as required")
+	}
+}
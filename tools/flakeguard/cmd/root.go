@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// ErrorExitCode is returned by flakeguard subcommands when they fail due to
+// an unrecoverable error.
+const ErrorExitCode = 1
+
+// RootCmd is the entrypoint cobra command for flakeguard. Its subcommands
+// are registered against it by the init function below.
+var RootCmd = &cobra.Command{
+	Use:   "flakeguard",
+	Short: "flakeguard aggregates and analyzes Go test results for flakiness",
+}
+
+func init() {
+	RootCmd.AddCommand(AggregateResultsCmd)
+	RootCmd.AddCommand(CompareReportsCmd)
+}
+
+// Execute runs the root command, exiting the process on error.
+func Execute() {
+	if err := RootCmd.Execute(); err != nil {
+		log.Fatal().Err(err).Msg("Error executing flakeguard command")
+	}
+}
@@ -0,0 +1,47 @@
+package reports
+
+import "fmt"
+
+// GitHubAnnotation is a single GitHub Actions workflow command
+// (`::error ...::` or `::warning ...::`) for one test.
+type GitHubAnnotation struct {
+	Level   string
+	File    string
+	Line    int
+	Message string
+}
+
+// String renders the annotation as a GitHub Actions workflow command line.
+func (a GitHubAnnotation) String() string {
+	if a.File == "" {
+		return fmt.Sprintf("::%s::%s", a.Level, a.Message)
+	}
+	return fmt.Sprintf("::%s file=%s,line=%d::%s", a.Level, a.File, a.Line, a.Message)
+}
+
+// BuildGitHubAnnotations returns one annotation per failed (error) or flaky
+// (warning) test in report, resolving each test's source location under
+// repoPath when possible.
+func BuildGitHubAnnotations(report TestReport, repoPath string, maxPassRatio float64) []GitHubAnnotation {
+	var annotations []GitHubAnnotation
+	for _, r := range report.Results {
+		if r.Skipped || r.PassRatio >= maxPassRatio {
+			continue
+		}
+
+		level := "warning"
+		message := fmt.Sprintf("%s.%s is flaky: pass ratio %.2f over %d runs", r.TestPackage, r.TestName, r.PassRatio, r.Runs)
+		if r.PassRatio == 0 {
+			level = "error"
+			message = fmt.Sprintf("%s.%s failed all %d runs", r.TestPackage, r.TestName, r.Runs)
+		}
+
+		annotation := GitHubAnnotation{Level: level, Message: message}
+		if file, line, ok := LocateTestSource(repoPath, r.TestPackage, r.TestName); ok {
+			annotation.File = file
+			annotation.Line = line
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations
+}
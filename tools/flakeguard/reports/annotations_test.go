@@ -0,0 +1,76 @@
+package reports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildGitHubAnnotations_ClassifiesErrorAndWarning(t *testing.T) {
+	report := TestReport{Results: []TestResult{
+		{TestPackage: "pkg", TestName: "TestAlwaysFails", Runs: 3, Successes: 0, PassRatio: 0},
+		{TestPackage: "pkg", TestName: "TestFlaky", Runs: 3, Successes: 2, PassRatio: 0.666},
+		{TestPackage: "pkg", TestName: "TestPasses", Runs: 3, Successes: 3, PassRatio: 1.0},
+		{TestPackage: "pkg", TestName: "TestSkipped", Skipped: true},
+	}}
+
+	annotations := BuildGitHubAnnotations(report, t.TempDir(), 1.0)
+	if len(annotations) != 2 {
+		t.Fatalf("expected 2 annotations, got %d", len(annotations))
+	}
+
+	byName := map[string]GitHubAnnotation{}
+	for _, a := range annotations {
+		byName[strings.Split(a.Message, " ")[0]] = a
+	}
+	if a, ok := byName["pkg.TestAlwaysFails"]; !ok || a.Level != "error" {
+		t.Errorf("expected an error annotation for TestAlwaysFails, got %+v", a)
+	}
+	if a, ok := byName["pkg.TestFlaky"]; !ok || a.Level != "warning" {
+		t.Errorf("expected a warning annotation for TestFlaky, got %+v", a)
+	}
+}
+
+func TestGitHubAnnotation_String(t *testing.T) {
+	withFile := GitHubAnnotation{Level: "error", File: "pkg/foo_test.go", Line: 12, Message: "boom"}
+	want := "::error file=pkg/foo_test.go,line=12::boom"
+	if got := withFile.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	withoutFile := GitHubAnnotation{Level: "warning", Message: "boom"}
+	if got := withoutFile.String(); got != "::warning::boom" {
+		t.Errorf("expected ::warning::boom, got %q", got)
+	}
+}
+
+func TestLocateTestSource_FindsDeclaration(t *testing.T) {
+	dir := t.TempDir()
+	pkgDir := filepath.Join(dir, "pkg")
+	if err := os.MkdirAll(pkgDir, 0755); err != nil {
+		t.Fatalf("creating package dir: %v", err)
+	}
+	content := "package pkg\n\nimport \"testing\"\n\nfunc TestSomething(t *testing.T) {}\n"
+	if err := os.WriteFile(filepath.Join(pkgDir, "something_test.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+
+	file, line, ok := LocateTestSource(dir, "pkg", "TestSomething")
+	if !ok {
+		t.Fatal("expected LocateTestSource to find the declaration")
+	}
+	if line != 5 {
+		t.Errorf("expected declaration on line 5, got %d", line)
+	}
+	if filepath.Base(file) != "something_test.go" {
+		t.Errorf("expected something_test.go, got %q", file)
+	}
+}
+
+func TestLocateTestSource_MissingPackageReturnsNotOK(t *testing.T) {
+	_, _, ok := LocateTestSource(t.TempDir(), "does/not/exist", "TestSomething")
+	if ok {
+		t.Error("expected ok=false for a nonexistent package directory")
+	}
+}
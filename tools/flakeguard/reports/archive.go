@@ -0,0 +1,280 @@
+package reports
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// archiveExtensions lists the file extensions that prepareResultsPath will
+// treat as archives to be transparently extracted before aggregation, rather
+// than as results files themselves.
+var archiveExtensions = []string{".tar.gz", ".tgz", ".zip"}
+
+// isArchive reports whether path has one of the recognized archive
+// extensions.
+func isArchive(path string) bool {
+	lower := strings.ToLower(path)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// prepareResultsPath inspects resultsPath and, if it is (or contains) any
+// archives, extracts them into a fresh directory under extractDir and
+// returns that directory along with a cleanup function that removes it.
+// Nested archives (an archive containing further archives) are extracted
+// recursively. If resultsPath contains no archives at all, it is returned
+// unchanged with a no-op cleanup.
+func prepareResultsPath(resultsPath, extractDir string) (string, func() error, error) {
+	info, err := os.Stat(resultsPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("stat %q: %w", resultsPath, err)
+	}
+
+	if info.Mode().IsRegular() && !isArchive(resultsPath) {
+		// A single, non-archive file: nothing to extract.
+		return resultsPath, func() error { return nil }, nil
+	}
+
+	var archivePaths []string
+	if info.IsDir() {
+		walkErr := filepath.Walk(resultsPath, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !fi.IsDir() && isArchive(path) {
+				archivePaths = append(archivePaths, path)
+			}
+			return nil
+		})
+		if walkErr != nil {
+			return "", nil, fmt.Errorf("scanning %q for archives: %w", resultsPath, walkErr)
+		}
+		if len(archivePaths) == 0 {
+			// Plain directory of already-extracted results.
+			return resultsPath, func() error { return nil }, nil
+		}
+	} else {
+		archivePaths = []string{resultsPath}
+	}
+
+	if extractDir == "" {
+		extractDir = os.TempDir()
+	}
+	workspace, err := os.MkdirTemp(extractDir, "flakeguard-results-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("creating extraction workspace under %q: %w", extractDir, err)
+	}
+	cleanup := func() error { return os.RemoveAll(workspace) }
+
+	processed := map[string]bool{}
+	for _, archivePath := range archivePaths {
+		if err := extractArchiveRecursive(archivePath, workspace, processed); err != nil {
+			_ = cleanup()
+			return "", nil, err
+		}
+	}
+
+	// Carry over any non-archive files that were sitting alongside the
+	// archives in a results directory, so loose JSON files still aggregate.
+	if info.IsDir() {
+		if err := copyNonArchiveFiles(resultsPath, workspace); err != nil {
+			_ = cleanup()
+			return "", nil, err
+		}
+	}
+
+	return workspace, cleanup, nil
+}
+
+// extractArchiveRecursive extracts archivePath into destDir, then recurses
+// into any archives it yields, so a tarball-of-tarballs (a common pattern
+// when per-shard CI runners each upload their own archive) is fully
+// unpacked. processed tracks every archive path already extracted in this
+// prepareResultsPath call, both to avoid extracting the same archive twice
+// and so each call only inspects the files it just wrote rather than
+// re-walking the whole (potentially large, shared) workspace.
+func extractArchiveRecursive(archivePath, destDir string, processed map[string]bool) error {
+	if processed[archivePath] {
+		return nil
+	}
+	processed[archivePath] = true
+
+	lower := strings.ToLower(archivePath)
+	var written []string
+	var err error
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		written, err = extractTarGz(archivePath, destDir)
+	case strings.HasSuffix(lower, ".zip"):
+		written, err = extractZip(archivePath, destDir)
+	default:
+		return fmt.Errorf("unsupported archive type: %q", archivePath)
+	}
+	if err != nil {
+		return fmt.Errorf("extracting %q: %w", archivePath, err)
+	}
+
+	for _, path := range written {
+		if isArchive(path) && !processed[path] {
+			if err := extractArchiveRecursive(path, destDir, processed); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// safeJoin joins destDir with the cleaned relative entry name and rejects
+// the result if it escapes destDir, guarding against zip-slip style path
+// traversal from malicious archive entries.
+func safeJoin(destDir, name string) (string, error) {
+	cleaned := filepath.Clean(filepath.Join(destDir, name))
+	destDirWithSep := filepath.Clean(destDir) + string(os.PathSeparator)
+	if !strings.HasPrefix(cleaned+string(os.PathSeparator), destDirWithSep) && cleaned != filepath.Clean(destDir) {
+		return "", fmt.Errorf("archive entry %q escapes extraction root %q", name, destDir)
+	}
+	return cleaned, nil
+}
+
+// extractTarGz extracts archivePath into destDir and returns the paths of
+// every regular file it wrote.
+func extractTarGz(archivePath, destDir string) ([]string, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var written []string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		target, err := safeJoin(destDir, hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return nil, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return nil, err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := io.Copy(out, tr); err != nil { //nolint:gosec // size is bounded by CI artifact limits
+				out.Close()
+				return nil, err
+			}
+			if err := out.Close(); err != nil {
+				return nil, err
+			}
+			written = append(written, target)
+		}
+	}
+}
+
+// extractZip extracts archivePath into destDir and returns the paths of
+// every regular file it wrote.
+func extractZip(archivePath, destDir string) ([]string, error) {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var written []string
+	for _, zf := range r.File {
+		target, err := safeJoin(destDir, zf.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if zf.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+		if err != nil {
+			rc.Close()
+			return nil, err
+		}
+		_, copyErr := io.Copy(out, rc) //nolint:gosec // size is bounded by CI artifact limits
+		rc.Close()
+		closeErr := out.Close()
+		if copyErr != nil {
+			return nil, copyErr
+		}
+		if closeErr != nil {
+			return nil, closeErr
+		}
+		written = append(written, target)
+	}
+	return written, nil
+}
+
+// copyNonArchiveFiles copies any regular, non-archive files found in srcDir
+// into destDir so they participate in aggregation alongside extracted
+// archive contents.
+func copyNonArchiveFiles(srcDir, destDir string) error {
+	return filepath.Walk(srcDir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() || isArchive(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destDir, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
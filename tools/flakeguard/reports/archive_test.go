@@ -0,0 +1,136 @@
+package reports
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("creating %q: %v", path, err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("writing header for %q: %v", name, err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("writing content for %q: %v", name, err)
+		}
+	}
+}
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("creating %q: %v", path, err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	defer zw.Close()
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("creating zip entry %q: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("writing zip entry %q: %v", name, err)
+		}
+	}
+}
+
+func TestPrepareResultsPath_ExtractsTarGz(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "results.tar.gz")
+	writeTarGz(t, archivePath, map[string]string{"shard1/result.json": `[]`})
+
+	extracted, cleanup, err := prepareResultsPath(archivePath, t.TempDir())
+	if err != nil {
+		t.Fatalf("prepareResultsPath returned error: %v", err)
+	}
+	defer cleanup()
+
+	if _, err := os.Stat(filepath.Join(extracted, "shard1", "result.json")); err != nil {
+		t.Errorf("expected extracted file to exist: %v", err)
+	}
+}
+
+func TestPrepareResultsPath_ExtractsNestedArchives(t *testing.T) {
+	dir := t.TempDir()
+	innerPath := filepath.Join(dir, "inner.zip")
+	writeZip(t, innerPath, map[string]string{"result.json": `[]`})
+
+	innerBytes, err := os.ReadFile(innerPath)
+	if err != nil {
+		t.Fatalf("reading inner archive: %v", err)
+	}
+
+	outerDir := t.TempDir()
+	outerPath := filepath.Join(outerDir, "outer.tar.gz")
+	writeTarGz(t, outerPath, map[string]string{"inner.zip": string(innerBytes)})
+
+	extracted, cleanup, err := prepareResultsPath(outerPath, t.TempDir())
+	if err != nil {
+		t.Fatalf("prepareResultsPath returned error: %v", err)
+	}
+	defer cleanup()
+
+	if _, err := os.Stat(filepath.Join(extracted, "result.json")); err != nil {
+		t.Errorf("expected nested archive contents to be extracted: %v", err)
+	}
+}
+
+func TestSafeJoin_RejectsZipSlip(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := safeJoin(dir, "../../etc/passwd"); err == nil {
+		t.Fatal("expected safeJoin to reject a path traversal entry, got nil error")
+	}
+}
+
+func TestSafeJoin_AllowsNestedPaths(t *testing.T) {
+	dir := t.TempDir()
+	target, err := safeJoin(dir, "a/b/result.json")
+	if err != nil {
+		t.Fatalf("safeJoin returned unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(target, dir+string(os.PathSeparator)) {
+		t.Errorf("expected target to be nested under %q, got %q", dir, target)
+	}
+}
+
+func TestPrepareResultsPath_PlainDirectoryUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "result.json")
+	if err := os.WriteFile(jsonPath, []byte(`[]`), 0644); err != nil {
+		t.Fatalf("writing %q: %v", jsonPath, err)
+	}
+
+	extracted, cleanup, err := prepareResultsPath(dir, t.TempDir())
+	if err != nil {
+		t.Fatalf("prepareResultsPath returned error: %v", err)
+	}
+	defer cleanup()
+
+	if extracted != dir {
+		t.Errorf("expected plain directory to be returned unchanged, got %q", extracted)
+	}
+}
@@ -0,0 +1,79 @@
+package reports
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// codeOwnersRule is a single non-comment, non-empty line from a CODEOWNERS
+// file: a path pattern and the owners responsible for paths matching it.
+type codeOwnersRule struct {
+	pattern string
+	owners  []string
+}
+
+// loadCodeOwners parses a CODEOWNERS file at path. Rules are returned in
+// file order; resolveOwners applies the usual "last matching rule wins"
+// semantics.
+func loadCodeOwners(path string) ([]codeOwnersRule, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []codeOwnersRule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		rules = append(rules, codeOwnersRule{pattern: fields[0], owners: fields[1:]})
+	}
+	return rules, scanner.Err()
+}
+
+// resolveOwners returns the owners of pkgPath according to rules, applying
+// the last matching rule (CODEOWNERS semantics: later, more specific rules
+// override earlier ones).
+func resolveOwners(rules []codeOwnersRule, pkgPath string) []string {
+	var owners []string
+	for _, rule := range rules {
+		if codeOwnersPatternMatches(rule.pattern, pkgPath) {
+			owners = rule.owners
+		}
+	}
+	return owners
+}
+
+// codeOwnersPatternMatches implements the small subset of CODEOWNERS
+// pattern matching flakeguard needs: a bare "*" matching everything, an
+// exact path, a "dir/" prefix match, or a "dir/*" match of paths directly
+// under dir. Other wildcards (such as "foo*") are compared literally.
+func codeOwnersPatternMatches(pattern, pkgPath string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	pkgPath = strings.TrimPrefix(pkgPath, "/")
+
+	if pattern == "*" {
+		return true
+	}
+	if strings.HasSuffix(pattern, "/") {
+		return strings.HasPrefix(pkgPath, pattern)
+	}
+	if strings.HasSuffix(pattern, "/*") {
+		dir := strings.TrimSuffix(pattern, "/*")
+		return filepath.Dir(pkgPath) == dir
+	}
+	return pattern == pkgPath
+}
@@ -0,0 +1,194 @@
+package reports
+
+import (
+	"math"
+)
+
+// ComparisonStatus classifus how a test's flakiness changed between a
+// baseline and a current aggregated report.
+type ComparisonStatus string
+
+const (
+	StatusNew         ComparisonStatus = "New"
+	StatusRemoved     ComparisonStatus = "Removed"
+	StatusFixed       ComparisonStatus = "Fixed"
+	StatusRegressed   ComparisonStatus = "Regressed"
+	StatusStillFlaky  ComparisonStatus = "StillFlaky"
+	StatusUnchanged   ComparisonStatus = "Unchanged"
+	StatusNoisyChange ComparisonStatus = "NoisyChange"
+)
+
+// TestComparison is the per-test outcome of comparing a baseline and current
+// TestResult for the same (package, test) pair.
+type TestComparison struct {
+	TestName    string           `json:"test_name"`
+	TestPackage string           `json:"test_package"`
+	CodeOwners  []string         `json:"code_owners,omitempty"`
+	Status      ComparisonStatus `json:"status"`
+
+	BaselinePassRatio float64 `json:"baseline_pass_ratio"`
+	CurrentPassRatio  float64 `json:"current_pass_ratio"`
+	PassRatioDelta    float64 `json:"pass_ratio_delta"`
+
+	BaselineRuns int `json:"baseline_runs"`
+	CurrentRuns  int `json:"current_runs"`
+	RunsDelta    int `json:"runs_delta"`
+
+	PValue *float64 `json:"p_value,omitempty"`
+}
+
+// ComparisonReport is the full result of comparing two aggregated
+// TestReports.
+type ComparisonReport struct {
+	BaselineReportID string `json:"baseline_report_id"`
+	CurrentReportID  string `json:"current_report_id"`
+
+	MaxPassRatio  float64 `json:"max_pass_ratio"`
+	Significance  float64 `json:"significance"`
+	MinRunsForSig int     `json:"min_runs_for_significance"`
+
+	Comparisons []TestComparison `json:"comparisons"`
+}
+
+// CompareOptions configures CompareReports.
+type CompareOptions struct {
+	// MaxPassRatio is the same threshold AggregateResultsCmd uses to decide
+	// whether a test counts as flaky/failing.
+	MaxPassRatio float64
+	// Significance is the p-value threshold below which a pass-ratio change
+	// is trusted as a real Regressed/Fixed rather than NoisyChange.
+	Significance float64
+	// MinRuns is the minimum number of runs a test must have in both
+	// reports for the two-proportion z-test to be applied at all; below it,
+	// no p-value is computed and a change that would be Regressed/Fixed is
+	// reported as NoisyChange.
+	MinRuns int
+}
+
+// CompareReports classifies every test present in baseline and/or current
+// and returns a ComparisonReport describing what changed.
+func CompareReports(baseline, current TestReport, opts CompareOptions) *ComparisonReport {
+	baselineByKey := resultsByKey(baseline.Results)
+	currentByKey := resultsByKey(current.Results)
+
+	keys := make(map[string]struct{}, len(baselineByKey)+len(currentByKey))
+	for k := range baselineByKey {
+		keys[k] = struct{}{}
+	}
+	for k := range currentByKey {
+		keys[k] = struct{}{}
+	}
+
+	report := &ComparisonReport{
+		BaselineReportID: baseline.ReportID,
+		CurrentReportID:  current.ReportID,
+		MaxPassRatio:     opts.MaxPassRatio,
+		Significance:     opts.Significance,
+		MinRunsForSig:    opts.MinRuns,
+		Comparisons:      make([]TestComparison, 0, len(keys)),
+	}
+
+	for key := range keys {
+		base, hasBase := baselineByKey[key]
+		cur, hasCurrent := currentByKey[key]
+		report.Comparisons = append(report.Comparisons, compareOne(base, hasBase, cur, hasCurrent, opts))
+	}
+
+	return report
+}
+
+func resultsByKey(results []TestResult) map[string]TestResult {
+	byKey := make(map[string]TestResult, len(results))
+	for _, r := range results {
+		byKey[r.TestPackage+"/"+r.TestName] = r
+	}
+	return byKey
+}
+
+func compareOne(base TestResult, hasBase bool, cur TestResult, hasCurrent bool, opts CompareOptions) TestComparison {
+	tc := TestComparison{}
+	switch {
+	case hasCurrent && !hasBase:
+		tc.TestName, tc.TestPackage, tc.CodeOwners = cur.TestName, cur.TestPackage, cur.CodeOwners
+	default:
+		tc.TestName, tc.TestPackage, tc.CodeOwners = base.TestName, base.TestPackage, base.CodeOwners
+	}
+
+	tc.BaselineRuns, tc.CurrentRuns = base.Runs, cur.Runs
+	tc.BaselinePassRatio, tc.CurrentPassRatio = base.PassRatio, cur.PassRatio
+	tc.PassRatioDelta = cur.PassRatio - base.PassRatio
+	tc.RunsDelta = cur.Runs - base.Runs
+
+	switch {
+	case hasCurrent && !hasBase:
+		tc.Status = StatusNew
+		return tc
+	case hasBase && !hasCurrent:
+		tc.Status = StatusRemoved
+		return tc
+	}
+
+	wasFlaky := base.PassRatio < opts.MaxPassRatio
+	isFlaky := cur.PassRatio < opts.MaxPassRatio
+
+	if !wasFlaky && !isFlaky {
+		tc.Status = StatusUnchanged
+		return tc
+	}
+
+	// Below MinRuns there isn't enough sample to run the significance test
+	// at all, so treat the change as noise rather than trusting the raw
+	// pass-ratio delta.
+	significant := false
+	if base.Runs >= opts.MinRuns && cur.Runs >= opts.MinRuns {
+		p := twoProportionZTestPValue(base.Successes, base.Runs, cur.Successes, cur.Runs)
+		tc.PValue = &p
+		significant = p < opts.Significance
+	}
+
+	switch {
+	case wasFlaky && !isFlaky:
+		tc.Status = StatusFixed
+	case !wasFlaky && isFlaky:
+		tc.Status = StatusRegressed
+	default: // wasFlaky && isFlaky
+		tc.Status = StatusStillFlaky
+	}
+
+	if !significant && tc.Status != StatusStillFlaky {
+		tc.Status = StatusNoisyChange
+	}
+
+	return tc
+}
+
+// twoProportionZTestPValue runs a two-tailed two-proportion z-test on
+// (successes1, n1) vs (successes2, n2) and returns the resulting p-value.
+// It returns 1 (no significance) for degenerate inputs where the pooled
+// variance is zero.
+func twoProportionZTestPValue(successes1, n1, successes2, n2 int) float64 {
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	p1 := float64(successes1) / float64(n1)
+	p2 := float64(successes2) / float64(n2)
+	pooled := float64(successes1+successes2) / float64(n1+n2)
+
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(n1) + 1/float64(n2)))
+	if se == 0 {
+		if p1 == p2 {
+			return 1
+		}
+		return 0
+	}
+
+	z := (p1 - p2) / se
+	return 2 * (1 - standardNormalCDF(math.Abs(z)))
+}
+
+// standardNormalCDF returns the cumulative distribution function of the
+// standard normal distribution at x.
+func standardNormalCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
@@ -0,0 +1,81 @@
+package reports
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RenderMarkdown renders report as a markdown summary suitable for posting
+// as a PR comment, grouping tests by CODEOWNERS team. Tests with no
+// resolved owners are grouped under "Unowned".
+func (report *ComparisonReport) RenderMarkdown() string {
+	var sb strings.Builder
+
+	counts := map[ComparisonStatus]int{}
+	for _, c := range report.Comparisons {
+		counts[c.Status]++
+	}
+
+	fmt.Fprintf(&sb, "## Flakiness comparison\n\n")
+	fmt.Fprintf(&sb, "%d regressed, %d fixed, %d still flaky, %d noisy, %d new, %d removed\n\n",
+		counts[StatusRegressed], counts[StatusFixed], counts[StatusStillFlaky],
+		counts[StatusNoisyChange], counts[StatusNew], counts[StatusRemoved])
+
+	interesting := make([]TestComparison, 0, len(report.Comparisons))
+	for _, c := range report.Comparisons {
+		if c.Status != StatusUnchanged {
+			interesting = append(interesting, c)
+		}
+	}
+	if len(interesting) == 0 {
+		sb.WriteString("No flakiness changes detected.\n")
+		return sb.String()
+	}
+
+	grouped := groupByOwner(interesting)
+	teams := make([]string, 0, len(grouped))
+	for team := range grouped {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+
+	for _, team := range teams {
+		fmt.Fprintf(&sb, "### %s\n\n", team)
+		fmt.Fprintf(&sb, "| Status | Test | Pass ratio | Runs | p-value |\n")
+		fmt.Fprintf(&sb, "|---|---|---|---|---|\n")
+
+		tests := grouped[team]
+		sort.Slice(tests, func(i, j int) bool {
+			return tests[i].TestPackage+"/"+tests[i].TestName < tests[j].TestPackage+"/"+tests[j].TestName
+		})
+
+		for _, c := range tests {
+			pValue := "-"
+			if c.PValue != nil {
+				pValue = fmt.Sprintf("%.4f", *c.PValue)
+			}
+			fmt.Fprintf(&sb, "| %s | `%s.%s` | %.2f → %.2f | %d → %d | %s |\n",
+				c.Status, c.TestPackage, c.TestName,
+				c.BaselinePassRatio, c.CurrentPassRatio,
+				c.BaselineRuns, c.CurrentRuns, pValue)
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func groupByOwner(comparisons []TestComparison) map[string][]TestComparison {
+	grouped := map[string][]TestComparison{}
+	for _, c := range comparisons {
+		owners := c.CodeOwners
+		if len(owners) == 0 {
+			owners = []string{"Unowned"}
+		}
+		for _, owner := range owners {
+			grouped[owner] = append(grouped[owner], c)
+		}
+	}
+	return grouped
+}
@@ -0,0 +1,77 @@
+package reports
+
+import "testing"
+
+func TestCompareReports_ClassifiesStatuses(t *testing.T) {
+	baseline := TestReport{Results: []TestResult{
+		{TestPackage: "pkg", TestName: "TestStable", Runs: 20, Successes: 20, PassRatio: 1.0},
+		{TestPackage: "pkg", TestName: "TestWasFlaky", Runs: 20, Successes: 10, PassRatio: 0.5},
+		{TestPackage: "pkg", TestName: "TestWasPassing", Runs: 20, Successes: 20, PassRatio: 1.0},
+		{TestPackage: "pkg", TestName: "TestRemoved", Runs: 20, Successes: 20, PassRatio: 1.0},
+	}}
+	current := TestReport{Results: []TestResult{
+		{TestPackage: "pkg", TestName: "TestStable", Runs: 20, Successes: 20, PassRatio: 1.0},
+		{TestPackage: "pkg", TestName: "TestWasFlaky", Runs: 20, Successes: 20, PassRatio: 1.0},
+		{TestPackage: "pkg", TestName: "TestWasPassing", Runs: 20, Successes: 5, PassRatio: 0.25},
+		{TestPackage: "pkg", TestName: "TestAdded", Runs: 20, Successes: 20, PassRatio: 1.0},
+	}}
+
+	report := CompareReports(baseline, current, CompareOptions{MaxPassRatio: 1.0, Significance: 0.05, MinRuns: 10})
+
+	byName := map[string]TestComparison{}
+	for _, c := range report.Comparisons {
+		byName[c.TestName] = c
+	}
+
+	if got := byName["TestStable"].Status; got != StatusUnchanged {
+		t.Errorf("TestStable: expected Unchanged, got %s", got)
+	}
+	if got := byName["TestWasFlaky"].Status; got != StatusFixed {
+		t.Errorf("TestWasFlaky: expected Fixed, got %s", got)
+	}
+	if got := byName["TestWasPassing"].Status; got != StatusRegressed {
+		t.Errorf("TestWasPassing: expected Regressed, got %s", got)
+	}
+	if got := byName["TestAdded"].Status; got != StatusNew {
+		t.Errorf("TestAdded: expected New, got %s", got)
+	}
+	if got := byName["TestRemoved"].Status; got != StatusRemoved {
+		t.Errorf("TestRemoved: expected Removed, got %s", got)
+	}
+}
+
+func TestCompareReports_SmallSampleChangeIsNoisy(t *testing.T) {
+	baseline := TestReport{Results: []TestResult{
+		{TestPackage: "pkg", TestName: "TestSmallSample", Runs: 3, Successes: 3, PassRatio: 1.0},
+	}}
+	current := TestReport{Results: []TestResult{
+		{TestPackage: "pkg", TestName: "TestSmallSample", Runs: 3, Successes: 2, PassRatio: 0.666},
+	}}
+
+	report := CompareReports(baseline, current, CompareOptions{MaxPassRatio: 1.0, Significance: 0.05, MinRuns: 10})
+	if len(report.Comparisons) != 1 {
+		t.Fatalf("expected 1 comparison, got %d", len(report.Comparisons))
+	}
+	// Below MinRuns, no z-test is applied, so the change can't be trusted as
+	// a real Regressed/Fixed: it's reported as NoisyChange instead.
+	if got := report.Comparisons[0].Status; got != StatusNoisyChange {
+		t.Errorf("expected NoisyChange for below-threshold sample size, got %s", got)
+	}
+	if report.Comparisons[0].PValue != nil {
+		t.Errorf("expected no p-value below MinRuns, got %v", *report.Comparisons[0].PValue)
+	}
+}
+
+func TestTwoProportionZTestPValue_IdenticalProportionsYieldHighPValue(t *testing.T) {
+	p := twoProportionZTestPValue(50, 100, 50, 100)
+	if p < 0.9 {
+		t.Errorf("expected p-value near 1 for identical proportions, got %f", p)
+	}
+}
+
+func TestTwoProportionZTestPValue_LargeDifferenceYieldsLowPValue(t *testing.T) {
+	p := twoProportionZTestPValue(100, 100, 50, 100)
+	if p > 0.01 {
+		t.Errorf("expected a small p-value for a large, well-sampled difference, got %f", p)
+	}
+}
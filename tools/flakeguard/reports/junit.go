@@ -0,0 +1,139 @@
+package reports
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// failedOutputKeyLess reports whether key a should sort before key b.
+// FailedOutputs keys are run numbers encoded as strings ("1", "2", ...,
+// "10"); comparing them as integers keeps "10" after "9" instead of
+// sorting lexically, which would put it before "2". Keys that aren't
+// parseable as integers fall back to a lexical comparison.
+func failedOutputKeyLess(a, b string) bool {
+	ai, aErr := strconv.Atoi(a)
+	bi, bErr := strconv.Atoi(b)
+	if aErr == nil && bErr == nil {
+		return ai < bi
+	}
+	return a < b
+}
+
+// JUnitTestSuites is the root element of a JUnit XML document.
+type JUnitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite corresponds to one Go package's worth of tests.
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase corresponds to one aggregated TestResult.
+type JUnitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	Classname  string           `xml:"classname,attr"`
+	Properties *JUnitProperties `xml:"properties,omitempty"`
+	Failure    *JUnitFailure    `xml:"failure,omitempty"`
+	Skipped    *JUnitSkipped    `xml:"skipped,omitempty"`
+}
+
+// JUnitFailure carries the last captured failure output for a test.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// JUnitSkipped marks a test case as skipped; it carries no data.
+type JUnitSkipped struct{}
+
+// JUnitProperties is a bag of free-form key/value metadata attached to a
+// test case.
+type JUnitProperties struct {
+	Properties []JUnitProperty `xml:"property"`
+}
+
+// JUnitProperty is a single name/value pair under <properties>.
+type JUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// BuildJUnitReport converts report into a JUnit testsuites document: one
+// <testsuite> per Go package, one <testcase> per test, with pass_ratio,
+// runs, and head_sha carried as <properties>.
+func BuildJUnitReport(report TestReport) JUnitTestSuites {
+	bySuite := map[string]*JUnitTestSuite{}
+	var order []string
+
+	for _, r := range report.Results {
+		suite, ok := bySuite[r.TestPackage]
+		if !ok {
+			suite = &JUnitTestSuite{Name: r.TestPackage}
+			bySuite[r.TestPackage] = suite
+			order = append(order, r.TestPackage)
+		}
+
+		tc := JUnitTestCase{
+			Name:      r.TestName,
+			Classname: r.TestPackage,
+			Properties: &JUnitProperties{Properties: []JUnitProperty{
+				{Name: "pass_ratio", Value: strconv.FormatFloat(r.PassRatio, 'f', 4, 64)},
+				{Name: "runs", Value: strconv.Itoa(r.Runs)},
+				{Name: "head_sha", Value: report.HeadSHA},
+			}},
+		}
+
+		switch {
+		case r.Skipped:
+			tc.Skipped = &JUnitSkipped{}
+			suite.Skipped++
+		case r.Failures > 0:
+			tc.Failure = &JUnitFailure{
+				Message: fmt.Sprintf("failed %d/%d runs", r.Failures, r.Runs),
+				Content: lastFailedOutput(r),
+			}
+			suite.Failures++
+		}
+
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	suites := make([]JUnitTestSuite, 0, len(order))
+	for _, name := range order {
+		suites = append(suites, *bySuite[name])
+	}
+	return JUnitTestSuites{Suites: suites}
+}
+
+// lastFailedOutput returns the final captured line of r's last
+// FailedOutputs entry, ordered by key, which is typically where `go test`
+// prints the assertion or panic message. FailedOutputs is a map keyed by
+// run number, so the keys are sorted numerically (not lexically, which
+// would put "10" before "2") to keep this both deterministic and correct
+// across calls.
+func lastFailedOutput(r TestResult) string {
+	if len(r.FailedOutputs) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(r.FailedOutputs))
+	for k := range r.FailedOutputs {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return failedOutputKeyLess(keys[i], keys[j]) })
+
+	outputs := r.FailedOutputs[keys[len(keys)-1]]
+	if len(outputs) == 0 {
+		return ""
+	}
+	return outputs[len(outputs)-1]
+}
@@ -0,0 +1,82 @@
+package reports
+
+import "testing"
+
+func TestBuildJUnitReport_GroupsByPackage(t *testing.T) {
+	report := TestReport{
+		HeadSHA: "deadbeef",
+		Results: []TestResult{
+			{TestPackage: "pkg/a", TestName: "TestPass", Runs: 2, Successes: 2, PassRatio: 1.0},
+			{TestPackage: "pkg/a", TestName: "TestFail", Runs: 2, Successes: 0, Failures: 2, PassRatio: 0,
+				FailedOutputs: map[string][]string{"1": {"assertion failed"}}},
+			{TestPackage: "pkg/b", TestName: "TestSkipped", Skipped: true},
+		},
+	}
+
+	suites := BuildJUnitReport(report)
+	if len(suites.Suites) != 2 {
+		t.Fatalf("expected 2 suites, got %d", len(suites.Suites))
+	}
+
+	var suiteA, suiteB *JUnitTestSuite
+	for i := range suites.Suites {
+		switch suites.Suites[i].Name {
+		case "pkg/a":
+			suiteA = &suites.Suites[i]
+		case "pkg/b":
+			suiteB = &suites.Suites[i]
+		}
+	}
+	if suiteA == nil || suiteB == nil {
+		t.Fatalf("expected suites for pkg/a and pkg/b, got %+v", suites.Suites)
+	}
+
+	if suiteA.Tests != 2 || suiteA.Failures != 1 {
+		t.Errorf("expected pkg/a to have 2 tests / 1 failure, got %d/%d", suiteA.Tests, suiteA.Failures)
+	}
+	if suiteB.Skipped != 1 {
+		t.Errorf("expected pkg/b to have 1 skipped test, got %d", suiteB.Skipped)
+	}
+
+	var failCase *JUnitTestCase
+	for i := range suiteA.TestCases {
+		if suiteA.TestCases[i].Name == "TestFail" {
+			failCase = &suiteA.TestCases[i]
+		}
+	}
+	if failCase == nil || failCase.Failure == nil {
+		t.Fatalf("expected TestFail to carry a <failure>, got %+v", failCase)
+	}
+	if failCase.Failure.Content != "assertion failed" {
+		t.Errorf("expected failure content from last FailedOutputs entry, got %q", failCase.Failure.Content)
+	}
+}
+
+func TestLastFailedOutput_DeterministicAcrossMultipleEntries(t *testing.T) {
+	r := TestResult{FailedOutputs: map[string][]string{
+		"1": {"first run failure"},
+		"2": {"second run failure"},
+		"3": {"third run failure"},
+	}}
+
+	want := lastFailedOutput(r)
+	for i := 0; i < 50; i++ {
+		if got := lastFailedOutput(r); got != want {
+			t.Fatalf("lastFailedOutput is not deterministic: got %q, want %q", got, want)
+		}
+	}
+	if want != "third run failure" {
+		t.Errorf("expected the highest-keyed entry's last output, got %q", want)
+	}
+}
+
+func TestLastFailedOutput_SortsKeysNumerically(t *testing.T) {
+	r := TestResult{FailedOutputs: map[string][]string{
+		"9":  {"run 9 failure"},
+		"10": {"run 10 failure"},
+	}}
+
+	if got := lastFailedOutput(r); got != "run 10 failure" {
+		t.Errorf("expected run 10 (the numerically last run) to win over run 9, got %q", got)
+	}
+}
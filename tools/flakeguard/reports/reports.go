@@ -0,0 +1,394 @@
+// Package reports provides the data structures and helpers used by flakeguard
+// to load raw `go test -json` output, aggregate it into a single report, and
+// persist that report to disk.
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// TestResult represents the aggregated outcome of a single test across one or
+// more runs.
+type TestResult struct {
+	TestName    string   `json:"test_name"`
+	TestPackage string   `json:"test_package"`
+	CodeOwners  []string `json:"code_owners,omitempty"`
+
+	Runs      int     `json:"runs"`
+	Successes int     `json:"successes"`
+	Failures  int     `json:"failures"`
+	Skips     int     `json:"skips"`
+	PassRatio float64 `json:"pass_ratio"`
+
+	Skipped  bool `json:"skipped"`
+	Panicked bool `json:"panicked"`
+	Timeout  bool `json:"timeout"`
+
+	Durations []time.Duration `json:"durations,omitempty"`
+
+	PassedOutputs  map[string][]string `json:"passed_outputs,omitempty"`
+	FailedOutputs  map[string][]string `json:"failed_outputs,omitempty"`
+	PackageOutputs []string            `json:"package_outputs,omitempty"`
+}
+
+// SummaryData holds aggregate counters describing an entire TestReport.
+type SummaryData struct {
+	UniqueTestsNum int `json:"unique_tests_num"`
+	PassedTests    int `json:"passed_tests"`
+	FailedTests    int `json:"failed_tests"`
+	SkippedTests   int `json:"skipped_tests"`
+	PanickedTests  int `json:"panicked_tests"`
+	RacedTests     int `json:"raced_tests"`
+}
+
+// TestReport is the top level aggregated output of flakeguard's
+// aggregate-results command.
+type TestReport struct {
+	ReportID      string `json:"report_id"`
+	GoProject     string `json:"go_project"`
+	RaceDetection bool   `json:"race_detection"`
+
+	RepoURL    string `json:"repo_url,omitempty"`
+	BranchName string `json:"branch_name,omitempty"`
+	HeadSHA    string `json:"head_sha,omitempty"`
+	BaseSHA    string `json:"base_sha,omitempty"`
+
+	GitHubWorkflowName   string `json:"github_workflow_name,omitempty"`
+	GitHubWorkflowRunURL string `json:"github_workflow_run_url,omitempty"`
+
+	ExcludedTests []string `json:"excluded_tests,omitempty"`
+	SelectedTests []string `json:"selected_tests,omitempty"`
+
+	SummaryData SummaryData  `json:"summary_data"`
+	Results     []TestResult `json:"results"`
+}
+
+// FileSystem abstracts the filesystem calls used by the reports package so
+// that callers (and tests) can swap in an in-memory implementation.
+type FileSystem interface {
+	MkdirAll(path string, perm os.FileMode) error
+	WriteFile(path string, data []byte, perm os.FileMode) error
+	ReadFile(path string) ([]byte, error)
+	Create(path string) (io.WriteCloser, error)
+}
+
+// OSFileSystem is the default FileSystem backed by the real operating system.
+type OSFileSystem struct{}
+
+func (OSFileSystem) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+func (OSFileSystem) WriteFile(path string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(path, data, perm)
+}
+
+func (OSFileSystem) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (OSFileSystem) Create(path string) (io.WriteCloser, error) {
+	return os.Create(path)
+}
+
+// aggregateOptions holds the optional metadata that can be attached to a
+// TestReport produced by LoadAndAggregate.
+type aggregateOptions struct {
+	repoPath             string
+	codeOwnersPath       string
+	reportID             string
+	branchName           string
+	baseSHA              string
+	headSHA              string
+	repoURL              string
+	githubWorkflowName   string
+	githubWorkflowRunURL string
+	extractDir           string
+	concurrency          int
+}
+
+// Option configures LoadAndAggregate.
+type Option func(*aggregateOptions)
+
+func WithRepoPath(path string) Option {
+	return func(o *aggregateOptions) { o.repoPath = path }
+}
+
+func WithCodeOwnersPath(path string) Option {
+	return func(o *aggregateOptions) { o.codeOwnersPath = path }
+}
+
+func WithReportID(id string) Option {
+	return func(o *aggregateOptions) { o.reportID = id }
+}
+
+func WithBranchName(name string) Option {
+	return func(o *aggregateOptions) { o.branchName = name }
+}
+
+func WithBaseSha(sha string) Option {
+	return func(o *aggregateOptions) { o.baseSHA = sha }
+}
+
+func WithHeadSha(sha string) Option {
+	return func(o *aggregateOptions) { o.headSHA = sha }
+}
+
+func WithRepoURL(url string) Option {
+	return func(o *aggregateOptions) { o.repoURL = url }
+}
+
+func WithGitHubWorkflowName(name string) Option {
+	return func(o *aggregateOptions) { o.githubWorkflowName = name }
+}
+
+func WithGitHubWorkflowRunURL(url string) Option {
+	return func(o *aggregateOptions) { o.githubWorkflowRunURL = url }
+}
+
+// WithExtractDir sets the directory under which archives found in
+// resultsPath are extracted. Defaults to os.TempDir when unset.
+func WithExtractDir(dir string) Option {
+	return func(o *aggregateOptions) { o.extractDir = dir }
+}
+
+// WithConcurrency sets how many results files are parsed in parallel.
+// Defaults to runtime.NumCPU() when unset or non-positive.
+func WithConcurrency(n int) Option {
+	return func(o *aggregateOptions) { o.concurrency = n }
+}
+
+// LoadAndAggregate walks resultsPath for JSON test result files, merges
+// results for the same (package, test) pair across files, and returns a
+// single aggregated TestReport.
+//
+// resultsPath may be a plain directory of JSON files, a single archive
+// (.tar.gz, .tgz, .zip), or a directory containing one or more archives
+// (optionally alongside loose JSON files). Archives are stream-extracted
+// into a temporary workspace, recursing into any archives nested inside
+// them, and that workspace is removed once aggregation completes.
+func LoadAndAggregate(resultsPath string, opts ...Option) (*TestReport, error) {
+	options := &aggregateOptions{reportID: uuidLikeID(), concurrency: runtime.NumCPU()}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.concurrency <= 0 {
+		options.concurrency = runtime.NumCPU()
+	}
+
+	preparedPath, cleanup, err := prepareResultsPath(resultsPath, options.extractDir)
+	if err != nil {
+		return nil, fmt.Errorf("preparing results path %q: %w", resultsPath, err)
+	}
+	defer func() {
+		if cleanupErr := cleanup(); cleanupErr != nil {
+			// Best-effort cleanup; surfacing this as a returned error would
+			// mask the real aggregation result.
+			fmt.Fprintf(os.Stderr, "warning: failed to clean up extraction workspace: %v\n", cleanupErr)
+		}
+	}()
+
+	codeOwnersRules, err := loadCodeOwners(options.codeOwnersPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading code owners from %q: %w", options.codeOwnersPath, err)
+	}
+
+	var resultsFiles []string
+	err = filepath.Walk(preparedPath, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if info.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+		resultsFiles = append(resultsFiles, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking results path %q: %w", resultsPath, err)
+	}
+
+	// Parsing happens concurrently, but merging must not: FailedOutputs keys
+	// collide across shards (each shard numbers its own runs "1", "2", ...),
+	// so the order results are folded in determines the order messages are
+	// appended under a colliding key. Parse into a slot per file and merge
+	// those slots back in resultsFiles order (not goroutine completion
+	// order) so the same input set always produces the same report.
+	parsed := make([][]TestResult, len(resultsFiles))
+
+	g, _ := errgroup.WithContext(context.Background())
+	g.SetLimit(options.concurrency)
+
+	for i, path := range resultsFiles {
+		i, path := i, path
+		g.Go(func() error {
+			results, err := parseResultsFile(path)
+			if err != nil {
+				return err
+			}
+			parsed[i] = results
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("parsing results files: %w", err)
+	}
+
+	merged := map[string]*TestResult{}
+	for _, results := range parsed {
+		mergeResults(merged, results)
+	}
+
+	report := &TestReport{
+		ReportID:             options.reportID,
+		RepoURL:              options.repoURL,
+		BranchName:           options.branchName,
+		HeadSHA:              options.headSHA,
+		BaseSHA:              options.baseSHA,
+		GitHubWorkflowName:   options.githubWorkflowName,
+		GitHubWorkflowRunURL: options.githubWorkflowRunURL,
+	}
+
+	report.Results = make([]TestResult, 0, len(merged))
+	for _, r := range merged {
+		if len(codeOwnersRules) > 0 {
+			r.CodeOwners = resolveOwners(codeOwnersRules, r.TestPackage)
+		}
+		report.Results = append(report.Results, *r)
+	}
+	sort.Slice(report.Results, func(i, j int) bool {
+		a, b := report.Results[i], report.Results[j]
+		if a.TestPackage != b.TestPackage {
+			return a.TestPackage < b.TestPackage
+		}
+		return a.TestName < b.TestName
+	})
+	report.SummaryData = summarize(report.Results)
+
+	return report, nil
+}
+
+// parseResultsFile parses a single JSON results file, which may contain
+// either a single TestReport or a bare slice of TestResult, and returns its
+// test results.
+func parseResultsFile(path string) ([]TestResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	var results []TestResult
+	var asReport TestReport
+	if err := json.Unmarshal(data, &asReport); err == nil && len(asReport.Results) > 0 {
+		results = asReport.Results
+	} else if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("parsing %q as test results: %w", path, err)
+	}
+	return results, nil
+}
+
+// mergeResults folds results into merged, keyed by (package, test), growing
+// merged in place. Callers parsing files concurrently must serialize calls
+// to mergeResults with their own lock.
+func mergeResults(merged map[string]*TestResult, results []TestResult) {
+	for _, r := range results {
+		key := r.TestPackage + "/" + r.TestName
+		if existing, ok := merged[key]; ok {
+			mergeInto(existing, r)
+		} else {
+			cp := r
+			merged[key] = &cp
+		}
+	}
+}
+
+// mergeInto folds the counters of r into existing, recomputing its pass
+// ratio.
+func mergeInto(existing *TestResult, r TestResult) {
+	existing.Runs += r.Runs
+	existing.Successes += r.Successes
+	existing.Failures += r.Failures
+	existing.Skips += r.Skips
+	existing.Panicked = existing.Panicked || r.Panicked
+	existing.Timeout = existing.Timeout || r.Timeout
+	existing.Durations = append(existing.Durations, r.Durations...)
+	if existing.Runs > 0 {
+		existing.PassRatio = float64(existing.Successes) / float64(existing.Runs)
+	}
+	for outcome, outputs := range r.PassedOutputs {
+		if existing.PassedOutputs == nil {
+			existing.PassedOutputs = map[string][]string{}
+		}
+		existing.PassedOutputs[outcome] = append(existing.PassedOutputs[outcome], outputs...)
+	}
+	for outcome, outputs := range r.FailedOutputs {
+		if existing.FailedOutputs == nil {
+			existing.FailedOutputs = map[string][]string{}
+		}
+		existing.FailedOutputs[outcome] = append(existing.FailedOutputs[outcome], outputs...)
+	}
+	existing.PackageOutputs = append(existing.PackageOutputs, r.PackageOutputs...)
+}
+
+func summarize(results []TestResult) SummaryData {
+	var s SummaryData
+	s.UniqueTestsNum = len(results)
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			s.SkippedTests++
+		case r.Panicked:
+			s.PanickedTests++
+		case r.Failures > 0:
+			s.FailedTests++
+		default:
+			s.PassedTests++
+		}
+	}
+	return s
+}
+
+// FilterTests returns the subset of results for which predicate returns true.
+func FilterTests(results []TestResult, predicate func(TestResult) bool) []TestResult {
+	filtered := make([]TestResult, 0, len(results))
+	for _, r := range results {
+		if predicate(r) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// SaveReport streams report to w as JSON: the header/summary fields are
+// written first, followed by the results array written one TestResult at a
+// time, so callers never need a second full copy of report.Results in
+// memory.
+func SaveReport(w io.Writer, report TestReport) error {
+	enc, err := NewStreamEncoder(w, report)
+	if err != nil {
+		return fmt.Errorf("opening report stream: %w", err)
+	}
+	for _, r := range report.Results {
+		if err := enc.WriteResult(r); err != nil {
+			return fmt.Errorf("writing test result %s/%s: %w", r.TestPackage, r.TestName, err)
+		}
+	}
+	return enc.Close()
+}
+
+// uuidLikeID generates a short, time-derived identifier used as a fallback
+// report ID when one isn't supplied by the caller.
+func uuidLikeID() string {
+	return fmt.Sprintf("report-%d", time.Now().UnixNano())
+}
@@ -0,0 +1,84 @@
+package reports
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeResultsFile(t *testing.T, dir, name string, results []TestResult) {
+	t.Helper()
+	data, err := json.Marshal(results)
+	if err != nil {
+		t.Fatalf("marshalling results: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		t.Fatalf("writing %q: %v", name, err)
+	}
+}
+
+func TestLoadAndAggregate_MergesAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeResultsFile(t, dir, "shard1.json", []TestResult{
+		{TestPackage: "pkg", TestName: "TestFlaky", Runs: 5, Successes: 4, PassRatio: 0.8},
+	})
+	writeResultsFile(t, dir, "shard2.json", []TestResult{
+		{TestPackage: "pkg", TestName: "TestFlaky", Runs: 5, Successes: 5, PassRatio: 1.0},
+	})
+
+	report, err := LoadAndAggregate(dir, WithConcurrency(2))
+	if err != nil {
+		t.Fatalf("LoadAndAggregate returned error: %v", err)
+	}
+
+	if len(report.Results) != 1 {
+		t.Fatalf("expected a single merged result, got %d", len(report.Results))
+	}
+	merged := report.Results[0]
+	if merged.Runs != 10 || merged.Successes != 9 {
+		t.Errorf("expected 10 runs / 9 successes after merge, got %d/%d", merged.Runs, merged.Successes)
+	}
+}
+
+func TestLoadAndAggregate_MergeOrderIsDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	writeResultsFile(t, dir, "shard1.json", []TestResult{
+		{TestPackage: "pkg", TestName: "TestFlaky", Runs: 1, Failures: 1,
+			FailedOutputs: map[string][]string{"1": {"shard1-message"}}},
+	})
+	writeResultsFile(t, dir, "shard2.json", []TestResult{
+		{TestPackage: "pkg", TestName: "TestFlaky", Runs: 1, Failures: 1,
+			FailedOutputs: map[string][]string{"1": {"shard2-message"}}},
+	})
+
+	for i := 0; i < 20; i++ {
+		report, err := LoadAndAggregate(dir, WithConcurrency(4))
+		if err != nil {
+			t.Fatalf("LoadAndAggregate returned error: %v", err)
+		}
+		if len(report.Results) != 1 {
+			t.Fatalf("expected a single merged result, got %d", len(report.Results))
+		}
+		got := report.Results[0].FailedOutputs["1"]
+		want := []string{"shard1-message", "shard2-message"}
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Fatalf("merge order is not deterministic: got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestLoadAndAggregate_DefaultsConcurrencyToPositive(t *testing.T) {
+	dir := t.TempDir()
+	writeResultsFile(t, dir, "shard1.json", []TestResult{
+		{TestPackage: "pkg", TestName: "TestOne", Runs: 1, Successes: 1, PassRatio: 1.0},
+	})
+
+	report, err := LoadAndAggregate(dir, WithConcurrency(-1))
+	if err != nil {
+		t.Fatalf("LoadAndAggregate returned error: %v", err)
+	}
+	if len(report.Results) != 1 {
+		t.Fatalf("expected a single result, got %d", len(report.Results))
+	}
+}
@@ -0,0 +1,54 @@
+package reports
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// LocateTestSource finds the file and line number where testName is
+// declared within testPackage, a path relative to repoPath. It returns
+// ok=false (rather than an error) when no declaration can be found, since
+// callers use this for best-effort annotations rather than anything that
+// should fail the aggregation.
+func LocateTestSource(repoPath, testPackage, testName string) (file string, line int, ok bool) {
+	dir := filepath.Join(repoPath, testPackage)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", 0, false
+	}
+
+	pattern := regexp.MustCompile(`^func\s+` + regexp.QuoteMeta(testName) + `\s*\(`)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if foundLine, found := scanForTestFunc(path, pattern); found {
+			return path, foundLine, true
+		}
+	}
+	return "", 0, false
+}
+
+// scanForTestFunc returns the 1-indexed line number of the first line in
+// path matching pattern.
+func scanForTestFunc(path string, pattern *regexp.Regexp) (int, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if pattern.MatchString(scanner.Text()) {
+			return lineNum, true
+		}
+	}
+	return 0, false
+}
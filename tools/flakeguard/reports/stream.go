@@ -0,0 +1,90 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// resultsFieldSentinel is what report.Results marshals to once nulled out;
+// NewStreamEncoder splices the open results array in its place.
+const resultsFieldSentinel = `"results":null}`
+
+// StreamEncoder writes a TestReport to an io.Writer incrementally: the
+// header/summary fields are written once up front, then each TestResult is
+// appended to the open "results" array one at a time via WriteResult. This
+// lets callers produce several differently-filtered report files from a
+// single in-memory Results slice without holding a second copy of it.
+type StreamEncoder struct {
+	w      io.Writer
+	wrote  int
+	closed bool
+}
+
+// NewStreamEncoder writes report's header fields (everything except
+// Results) to w and opens the "results" array, returning an encoder whose
+// WriteResult appends elements to it.
+func NewStreamEncoder(w io.Writer, report TestReport) (*StreamEncoder, error) {
+	header := report
+	header.Results = nil
+
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling report header: %w", err)
+	}
+	if !bytes.HasSuffix(data, []byte(resultsFieldSentinel)) {
+		return nil, fmt.Errorf("unexpected report header encoding")
+	}
+	prefix := data[:len(data)-len(resultsFieldSentinel)]
+
+	if _, err := w.Write(prefix); err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(w, `"results":[`); err != nil {
+		return nil, err
+	}
+
+	return &StreamEncoder{w: w}, nil
+}
+
+// WriteResult appends a single TestResult to the open results array.
+func (e *StreamEncoder) WriteResult(r TestResult) error {
+	if e.wrote > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(r); err != nil {
+		return fmt.Errorf("encoding test result %s/%s: %w", r.TestPackage, r.TestName, err)
+	}
+
+	if _, err := e.w.Write(bytes.TrimRight(buf.Bytes(), "\n")); err != nil {
+		return err
+	}
+	e.wrote++
+	return nil
+}
+
+// Close closes the results array and the outer report object. It does not
+// close the underlying writer.
+func (e *StreamEncoder) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	_, err := io.WriteString(e.w, "]}")
+	return err
+}
+
+// StripLogs returns a copy of r with its output fields cleared, for writing
+// a "no logs" variant of a report without mutating the shared Results
+// slice.
+func StripLogs(r TestResult) TestResult {
+	r.PassedOutputs = nil
+	r.FailedOutputs = nil
+	r.PackageOutputs = nil
+	return r
+}
@@ -0,0 +1,66 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestSaveReport_RoundTrips(t *testing.T) {
+	report := TestReport{
+		ReportID: "test-report",
+		Results: []TestResult{
+			{TestPackage: "pkg/a", TestName: "TestOne", Runs: 2, Successes: 2, PassRatio: 1.0},
+			{TestPackage: "pkg/b", TestName: "TestTwo", Runs: 2, Successes: 1, PassRatio: 0.5},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := SaveReport(&buf, report); err != nil {
+		t.Fatalf("SaveReport returned error: %v", err)
+	}
+
+	var roundTripped TestReport
+	if err := json.Unmarshal(buf.Bytes(), &roundTripped); err != nil {
+		t.Fatalf("decoding streamed report: %v", err)
+	}
+
+	if roundTripped.ReportID != report.ReportID {
+		t.Errorf("expected report ID %q, got %q", report.ReportID, roundTripped.ReportID)
+	}
+	if len(roundTripped.Results) != len(report.Results) {
+		t.Fatalf("expected %d results, got %d", len(report.Results), len(roundTripped.Results))
+	}
+}
+
+func TestSaveReport_EmptyResults(t *testing.T) {
+	var buf bytes.Buffer
+	if err := SaveReport(&buf, TestReport{ReportID: "empty"}); err != nil {
+		t.Fatalf("SaveReport returned error: %v", err)
+	}
+
+	var roundTripped TestReport
+	if err := json.Unmarshal(buf.Bytes(), &roundTripped); err != nil {
+		t.Fatalf("decoding streamed report: %v", err)
+	}
+	if len(roundTripped.Results) != 0 {
+		t.Errorf("expected no results, got %d", len(roundTripped.Results))
+	}
+}
+
+func TestStripLogs_ClearsOutputFields(t *testing.T) {
+	r := TestResult{
+		TestName:       "TestWithLogs",
+		FailedOutputs:  map[string][]string{"1": {"boom"}},
+		PassedOutputs:  map[string][]string{"1": {"ok"}},
+		PackageOutputs: []string{"go test output"},
+	}
+	stripped := StripLogs(r)
+
+	if stripped.FailedOutputs != nil || stripped.PassedOutputs != nil || stripped.PackageOutputs != nil {
+		t.Errorf("expected all output fields cleared, got %+v", stripped)
+	}
+	if r.FailedOutputs == nil {
+		t.Error("expected original result to be unmodified by StripLogs")
+	}
+}